@@ -0,0 +1,48 @@
+// Copyright 2017 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+import "time"
+
+// Entry statuses.
+const (
+	EntryStatusUnread  = "unread"
+	EntryStatusRead    = "read"
+	EntryStatusRemoved = "removed"
+)
+
+// Entry represents a feed item.
+type Entry struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	FeedID      int64     `json:"feed_id"`
+	Status      string    `json:"status"`
+	Hash        string    `json:"hash"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	CommentsURL string    `json:"comments_url"`
+	Date        time.Time `json:"published_at"`
+	Content     string    `json:"content"`
+	Author      string    `json:"author"`
+
+	Enclosures EnclosureList `json:"enclosures,omitempty"`
+	Podcast    *EntryPodcast `json:"podcast,omitempty"`
+
+	// Tags holds category values for simple tag-based filtering, which the
+	// API and web UI expose as a filter facet. Categories holds the same
+	// data alongside the domain/scheme attribute, for consumers that need
+	// the full taxonomy.
+	Tags       []string        `json:"tags,omitempty"`
+	Categories []EntryCategory `json:"categories,omitempty"`
+	Source     *EntrySource    `json:"source,omitempty"`
+
+	DublinCore *EntryDublinCore `json:"dublin_core,omitempty"`
+	Geo        *EntryGeo        `json:"geo,omitempty"`
+
+	Feed *Feed `json:"-"`
+}
+
+// Entries represents a list of entries.
+type Entries []*Entry
@@ -0,0 +1,12 @@
+// Copyright 2017 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+// EntryCategory is a single <category>, preserving the domain/scheme
+// attribute alongside its value.
+type EntryCategory struct {
+	Value  string `json:"value"`
+	Domain string `json:"domain,omitempty"`
+}
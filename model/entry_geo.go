@@ -0,0 +1,26 @@
+// Copyright 2017 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+// EntryDublinCore collects the Dublin Core Terms fields that have no
+// dedicated home on Entry (dc:creator and dc:date feed Entry.Author and
+// Entry.Date directly).
+type EntryDublinCore struct {
+	Subject    []string `json:"subject,omitempty"`
+	Publisher  string   `json:"publisher,omitempty"`
+	Rights     string   `json:"rights,omitempty"`
+	Language   string   `json:"language,omitempty"`
+	Identifier string   `json:"identifier,omitempty"`
+}
+
+// EntryGeo is the location of an entry as given by a GeoRSS Simple element.
+// Coordinates are kept in their original GeoRSS textual form (space/comma
+// separated lat/lon pairs) rather than parsed, since the geometry's
+// cardinality varies by Type; rendering a map view or geographic filter
+// from them is left to the API/UI layer.
+type EntryGeo struct {
+	Type        string `json:"type"`
+	Coordinates string `json:"coordinates"`
+}
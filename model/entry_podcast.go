@@ -0,0 +1,42 @@
+// Copyright 2017 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+// EntryPodcast holds the per-episode metadata parsed from the iTunes
+// Podcasting DTD and the Podcasting 2.0 namespace. It is nil for entries
+// that carry none of these tags. Duration, ChaptersURL and TranscriptURL
+// are meant for the entry view's podcast player controls; the view itself
+// lives outside this package.
+type EntryPodcast struct {
+	Duration      int    `json:"duration"`
+	Episode       string `json:"episode"`
+	Season        string `json:"season"`
+	EpisodeType   string `json:"episode_type"`
+	ImageURL      string `json:"image_url"`
+	Explicit      bool   `json:"explicit"`
+	Subtitle      string `json:"subtitle"`
+	TranscriptURL string `json:"transcript_url"`
+	ChaptersURL   string `json:"chapters_url"`
+
+	Persons         []EntryPodcastPerson         `json:"persons,omitempty"`
+	ValueRecipients []EntryPodcastValueRecipient `json:"value_recipients,omitempty"`
+}
+
+// EntryPodcastPerson represents a podcast:person credit.
+type EntryPodcastPerson struct {
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	Group    string `json:"group"`
+	ImageURL string `json:"image_url"`
+	URL      string `json:"url"`
+}
+
+// EntryPodcastValueRecipient represents a podcast:valueRecipient payment split.
+type EntryPodcastValueRecipient struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Address string `json:"address"`
+	Split   string `json:"split"`
+}
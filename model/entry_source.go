@@ -0,0 +1,12 @@
+// Copyright 2017 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+// EntrySource describes the feed an entry was re-syndicated from, as
+// advertised by RSS <source url="..."> or Atom <source>.
+type EntrySource struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
@@ -0,0 +1,41 @@
+// Copyright 2017 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+import "time"
+
+// Feed represents a subscribed feed.
+type Feed struct {
+	ID      int64  `json:"id"`
+	UserID  int64  `json:"user_id"`
+	Title   string `json:"title"`
+	SiteURL string `json:"site_url"`
+	FeedURL string `json:"feed_url"`
+
+	// HubURL is the WebSub (PubSubHubbub) hub advertised by the feed, if any.
+	HubURL string `json:"hub_url,omitempty"`
+	// CloudRegisterURL is the RSS Cloud endpoint to call to register for
+	// push notifications, populated only when the channel advertises a
+	// protocol miniflux implements (see rss.rssCloud.RegisterURL).
+	CloudRegisterURL string `json:"-"`
+	// CloudRegisterProcedure is the registerProcedure name to invoke at
+	// CloudRegisterURL.
+	CloudRegisterProcedure string `json:"-"`
+	// WebSubCallbackToken identifies this feed's subscription on our own
+	// callback endpoint; generated once on first subscribe.
+	WebSubCallbackToken string `json:"-"`
+	// WebSubLeaseExpiresAt is when the hub's subscription lease runs out.
+	// It's only set once the hub's verification GET confirms the
+	// subscription; a zero value means there is no active WebSub
+	// subscription yet (or one is still pending verification).
+	WebSubLeaseExpiresAt time.Time `json:"-"`
+	// CloudRegistrationExpiresAt is when our RSS Cloud registration needs
+	// renewing. Unlike WebSub, RSS Cloud has no lease negotiation, so this
+	// is just our own re-registration schedule, not a value the publisher
+	// reports. A zero value means there is no active registration.
+	CloudRegistrationExpiresAt time.Time `json:"-"`
+
+	Entries Entries `json:"entries,omitempty"`
+}
@@ -0,0 +1,259 @@
+// Copyright 2017 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package atom // import "miniflux.app/reader/atom"
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"miniflux.app/crypto"
+	"miniflux.app/logger"
+	"miniflux.app/model"
+	"miniflux.app/reader/date"
+	"miniflux.app/reader/sanitizer"
+	"miniflux.app/url"
+)
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Authors []atomPerson `xml:"author"`
+	Links   []atomLink   `xml:"link"`
+	Entries []atomEntry  `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	URL  string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type atomText struct {
+	Data  string `xml:",chardata"`
+	Type  string `xml:"type,attr"`
+	XHTML string `xml:",innerxml"`
+}
+
+type atomCategory struct {
+	Term   string `xml:"term,attr"`
+	Scheme string `xml:"scheme,attr"`
+	Label  string `xml:"label,attr"`
+}
+
+type atomSource struct {
+	ID    string     `xml:"id"`
+	Title string     `xml:"title"`
+	Links []atomLink `xml:"link"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Authors    []atomPerson   `xml:"author"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    atomText       `xml:"summary"`
+	Content    atomText       `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+	Source     *atomSource    `xml:"source"`
+}
+
+func (a *atomFeed) siteURL() string {
+	for _, link := range a.Links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return strings.TrimSpace(link.URL)
+		}
+	}
+
+	return ""
+}
+
+func (a *atomFeed) feedURL() string {
+	for _, link := range a.Links {
+		if link.Rel == "self" {
+			return strings.TrimSpace(link.URL)
+		}
+	}
+
+	return ""
+}
+
+// Transform converts a parsed Atom document into the model used across the
+// rest of the application, mirroring rss.rssFeed.Transform.
+func (a *atomFeed) Transform() *model.Feed {
+	feed := new(model.Feed)
+	feed.SiteURL = a.siteURL()
+	feed.FeedURL = a.feedURL()
+	feed.Title = strings.TrimSpace(a.Title)
+
+	if feed.Title == "" {
+		feed.Title = feed.SiteURL
+	}
+
+	for _, item := range a.Entries {
+		entry := item.Transform()
+		entry.Author = strings.TrimSpace(sanitizer.StripTags(entry.Author))
+
+		if entry.URL == "" {
+			entry.URL = feed.SiteURL
+		} else {
+			entryURL, err := url.AbsoluteURL(feed.SiteURL, entry.URL)
+			if err == nil {
+				entry.URL = entryURL
+			}
+		}
+
+		if entry.Title == "" {
+			entry.Title = entry.URL
+		}
+
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return feed
+}
+
+func (e *atomEntry) url() string {
+	for _, link := range e.Links {
+		if (link.Rel == "" || link.Rel == "alternate") && link.URL != "" {
+			return strings.TrimSpace(link.URL)
+		}
+	}
+
+	return ""
+}
+
+func (e *atomEntry) author() string {
+	for _, author := range e.Authors {
+		if author.Name != "" {
+			return author.Name
+		}
+	}
+
+	return ""
+}
+
+func (e *atomEntry) publishedDate() time.Time {
+	value := e.Updated
+	if e.Published != "" {
+		value = e.Published
+	}
+
+	if value != "" {
+		result, err := date.Parse(value)
+		if err != nil {
+			logger.Error("atom: %v", err)
+			return time.Now()
+		}
+
+		return result
+	}
+
+	return time.Now()
+}
+
+func (e *atomEntry) content() string {
+	if e.Content.Data != "" || e.Content.XHTML != "" {
+		if e.Content.XHTML != "" {
+			return e.Content.XHTML
+		}
+		return e.Content.Data
+	}
+
+	if e.Summary.XHTML != "" {
+		return e.Summary.XHTML
+	}
+
+	return e.Summary.Data
+}
+
+func (e *atomEntry) hash() string {
+	for _, value := range []string{e.ID, e.url()} {
+		if value != "" {
+			return crypto.Hash(value)
+		}
+	}
+
+	return ""
+}
+
+// tags returns category terms for simple tag-based filtering. The scheme
+// attribute is preserved separately by entryCategories; collapsing it here
+// would discard the taxonomy a category belongs to.
+func (e *atomEntry) tags() []string {
+	var tags []string
+
+	for _, category := range e.Categories {
+		if term := strings.TrimSpace(category.Term); term != "" {
+			tags = append(tags, term)
+		}
+	}
+
+	return tags
+}
+
+// entryCategories returns the full <category> elements, including the
+// scheme attribute (Atom's equivalent of RSS's category "domain").
+func (e *atomEntry) entryCategories() []model.EntryCategory {
+	var categories []model.EntryCategory
+
+	for _, category := range e.Categories {
+		term := strings.TrimSpace(category.Term)
+		if term == "" {
+			continue
+		}
+
+		categories = append(categories, model.EntryCategory{
+			Value:  term,
+			Domain: strings.TrimSpace(category.Scheme),
+		})
+	}
+
+	return categories
+}
+
+// entrySource describes the originating feed entry for re-syndicated
+// content, as advertised by Atom's <source> element.
+func (e *atomEntry) entrySource() *model.EntrySource {
+	if e.Source == nil {
+		return nil
+	}
+
+	title := strings.TrimSpace(e.Source.Title)
+	sourceURL := ""
+	for _, link := range e.Source.Links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			sourceURL = strings.TrimSpace(link.URL)
+			break
+		}
+	}
+
+	if title == "" && sourceURL == "" {
+		return nil
+	}
+
+	return &model.EntrySource{Title: title, URL: sourceURL}
+}
+
+func (e *atomEntry) Transform() *model.Entry {
+	entry := new(model.Entry)
+	entry.URL = e.url()
+	entry.Date = e.publishedDate()
+	entry.Author = e.author()
+	entry.Hash = e.hash()
+	entry.Content = e.content()
+	entry.Title = strings.TrimSpace(e.Title)
+	entry.Tags = e.tags()
+	entry.Categories = e.entryCategories()
+	entry.Source = e.entrySource()
+	return entry
+}
@@ -0,0 +1,156 @@
+// Copyright 2017 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package atom // import "miniflux.app/reader/atom"
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func unmarshalEntry(t *testing.T, data string) *atomEntry {
+	t.Helper()
+	var entry atomEntry
+	if err := xml.Unmarshal([]byte(data), &entry); err != nil {
+		t.Fatalf("unable to unmarshal entry: %v", err)
+	}
+	return &entry
+}
+
+func TestParseMinimalAtomDocument(t *testing.T) {
+	var feed atomFeed
+	data := `
+		<feed xmlns="http://www.w3.org/2005/Atom">
+			<title>Example Feed</title>
+			<link href="https://example.org/"></link>
+			<link rel="self" href="https://example.org/feed.xml"></link>
+			<entry>
+				<title>Example Entry</title>
+				<link href="https://example.org/post"></link>
+				<id>urn:uuid:1</id>
+				<updated>2021-01-02T15:04:05Z</updated>
+				<summary>Hello</summary>
+			</entry>
+		</feed>
+	`
+	if err := xml.Unmarshal([]byte(data), &feed); err != nil {
+		t.Fatalf("unable to unmarshal feed: %v", err)
+	}
+
+	result := feed.Transform()
+	if result.Title != "Example Feed" {
+		t.Errorf("Title = %q, want %q", result.Title, "Example Feed")
+	}
+	if result.SiteURL != "https://example.org/" {
+		t.Errorf("SiteURL = %q, want %q", result.SiteURL, "https://example.org/")
+	}
+	if result.FeedURL != "https://example.org/feed.xml" {
+		t.Errorf("FeedURL = %q, want %q", result.FeedURL, "https://example.org/feed.xml")
+	}
+	if len(result.Entries) != 1 {
+		t.Fatalf("len(Entries) = %d, want 1", len(result.Entries))
+	}
+	if entry := result.Entries[0]; entry.Title != "Example Entry" || entry.URL != "https://example.org/post" {
+		t.Errorf("entry = %+v, want title %q and url %q", entry, "Example Entry", "https://example.org/post")
+	}
+}
+
+func TestAtomEntryHashPrefersIDOverURL(t *testing.T) {
+	entry := unmarshalEntry(t, `<entry><id>urn:uuid:1</id><link href="https://example.org/post"></link></entry>`)
+	expected := unmarshalEntry(t, `<entry><id>urn:uuid:1</id></entry>`).hash()
+	if hash := entry.hash(); hash != expected {
+		t.Errorf("hash() = %q, want the id-derived hash %q", hash, expected)
+	}
+}
+
+func TestAtomEntryHashFallsBackToURL(t *testing.T) {
+	entry := unmarshalEntry(t, `<entry><link href="https://example.org/post"></link></entry>`)
+	if entry.hash() == "" {
+		t.Error("hash() should fall back to the url when there's no id")
+	}
+}
+
+func TestAtomEntryContentPrefersContentOverSummary(t *testing.T) {
+	entry := unmarshalEntry(t, `<entry><content>full content</content><summary>short summary</summary></entry>`)
+	if content := entry.content(); content != "full content" {
+		t.Errorf("content() = %q, want %q", content, "full content")
+	}
+}
+
+func TestAtomEntryContentFallsBackToSummary(t *testing.T) {
+	entry := unmarshalEntry(t, `<entry><summary>short summary</summary></entry>`)
+	if content := entry.content(); content != "short summary" {
+		t.Errorf("content() = %q, want %q", content, "short summary")
+	}
+}
+
+func TestAtomEntryContentPrefersXHTML(t *testing.T) {
+	entry := unmarshalEntry(t, `<entry><content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml">rich <b>content</b></div></content></entry>`)
+	if content := entry.content(); content != `<div xmlns="http://www.w3.org/1999/xhtml">rich <b>content</b></div>` {
+		t.Errorf("content() = %q, want the inner xhtml markup", content)
+	}
+}
+
+func TestAtomEntryPublishedDatePrefersPublishedOverUpdated(t *testing.T) {
+	entry := unmarshalEntry(t, `<entry><published>2021-01-01T00:00:00Z</published><updated>2021-06-01T00:00:00Z</updated></entry>`)
+	date := entry.publishedDate()
+	if date.Year() != 2021 || date.Month() != 1 {
+		t.Errorf("publishedDate() = %v, want the published value (January 2021)", date)
+	}
+}
+
+func TestAtomEntryPublishedDateFallsBackToUpdated(t *testing.T) {
+	entry := unmarshalEntry(t, `<entry><updated>2021-06-01T00:00:00Z</updated></entry>`)
+	date := entry.publishedDate()
+	if date.Year() != 2021 || date.Month() != 6 {
+		t.Errorf("publishedDate() = %v, want the updated value (June 2021)", date)
+	}
+}
+
+func TestAtomEntryTags(t *testing.T) {
+	entry := unmarshalEntry(t, `
+		<entry>
+			<category term="go" scheme="https://example.org/tags"></category>
+			<category term=" " ></category>
+			<category term="atom"></category>
+		</entry>
+	`)
+
+	tags := entry.tags()
+	if len(tags) != 2 || tags[0] != "go" || tags[1] != "atom" {
+		t.Errorf("tags() = %v, want [go atom], blank terms should be skipped", tags)
+	}
+}
+
+func TestAtomEntryCategoriesPreserveScheme(t *testing.T) {
+	entry := unmarshalEntry(t, `<entry><category term="go" scheme="https://example.org/tags"></category></entry>`)
+
+	categories := entry.entryCategories()
+	if len(categories) != 1 || categories[0].Value != "go" || categories[0].Domain != "https://example.org/tags" {
+		t.Errorf("entryCategories() = %+v, want a single category with value=go domain=https://example.org/tags", categories)
+	}
+}
+
+func TestAtomEntrySource(t *testing.T) {
+	entry := unmarshalEntry(t, `
+		<entry>
+			<source>
+				<title>Originating Feed</title>
+				<link href="https://origin.example.org/"></link>
+			</source>
+		</entry>
+	`)
+
+	source := entry.entrySource()
+	if source == nil || source.Title != "Originating Feed" || source.URL != "https://origin.example.org/" {
+		t.Errorf("entrySource() = %+v, want title=Originating Feed url=https://origin.example.org/", source)
+	}
+}
+
+func TestAtomEntrySourceNilWhenAbsent(t *testing.T) {
+	entry := unmarshalEntry(t, `<entry></entry>`)
+	if source := entry.entrySource(); source != nil {
+		t.Errorf("entrySource() = %+v, want nil when no <source> element is present", source)
+	}
+}
@@ -6,6 +6,7 @@ package rss // import "miniflux.app/reader/rss"
 
 import (
 	"encoding/xml"
+	"fmt"
 	"path"
 	"strconv"
 	"strings"
@@ -29,9 +30,56 @@ type rssFeed struct {
 	Description  string    `xml:"channel>description"`
 	PubDate      string    `xml:"channel>pubDate"`
 	ItunesAuthor string    `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd channel>author"`
+	Cloud        *rssCloud `xml:"channel>cloud"`
 	Items        []rssItem `xml:"channel>item"`
 }
 
+type rssCloud struct {
+	Domain                string `xml:"domain,attr"`
+	Port                  string `xml:"port,attr"`
+	Path                  string `xml:"path,attr"`
+	RegisterProcedureAttr string `xml:"registerProcedure,attr"`
+	Protocol              string `xml:"protocol,attr"`
+}
+
+// RegisterURL builds the endpoint miniflux must call to register as a
+// subscriber for RSS Cloud notifications. Only the REST ("http-post")
+// notification profile is implemented; registerProcedure is the procedure
+// name the subscriber invokes at that endpoint, not a URL the publisher
+// calls back on, so it's surfaced separately via RegisterProcedure().
+func (c *rssCloud) RegisterURL() string {
+	if c == nil || c.Domain == "" || !isSupportedCloudProtocol(c.Protocol) {
+		return ""
+	}
+
+	port := c.Port
+	if port == "" {
+		port = "80"
+	}
+
+	return fmt.Sprintf("http://%s:%s%s", c.Domain, port, c.Path)
+}
+
+// RegisterProcedure returns the registerProcedure attribute that names the
+// registration call to make at RegisterURL(), or an empty string if the
+// channel's cloud protocol isn't one miniflux implements.
+func (c *rssCloud) RegisterProcedure() string {
+	if c == nil || !isSupportedCloudProtocol(c.Protocol) {
+		return ""
+	}
+
+	return c.RegisterProcedureAttr
+}
+
+func isSupportedCloudProtocol(protocol string) bool {
+	switch strings.ToLower(protocol) {
+	case "", "http-post", "rest":
+		return true
+	default:
+		return false
+	}
+}
+
 type rssLink struct {
 	XMLName xml.Name
 	Data    string `xml:",chardata"`
@@ -66,22 +114,107 @@ func (enclosure *rssEnclosure) Size() int64 {
 }
 
 type rssItem struct {
-	GUID              string           `xml:"guid"`
-	Title             string           `xml:"title"`
-	Links             []rssLink        `xml:"link"`
-	OriginalLink      string           `xml:"http://rssnamespace.org/feedburner/ext/1.0 origLink"`
-	CommentLinks      []rssCommentLink `xml:"comments"`
-	Description       string           `xml:"description"`
-	EncodedContent    string           `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
-	PubDate           string           `xml:"pubDate"`
-	Date              string           `xml:"http://purl.org/dc/elements/1.1/ date"`
-	Authors           []rssAuthor      `xml:"author"`
-	Creator           string           `xml:"http://purl.org/dc/elements/1.1/ creator"`
-	EnclosureLinks    []rssEnclosure   `xml:"enclosure"`
-	OrigEnclosureLink string           `xml:"http://rssnamespace.org/feedburner/ext/1.0 origEnclosureLink"`
+	GUID               string              `xml:"guid"`
+	Title              string              `xml:"title"`
+	Links              []rssLink           `xml:"link"`
+	OriginalLink       string              `xml:"http://rssnamespace.org/feedburner/ext/1.0 origLink"`
+	CommentLinks       []rssCommentLink    `xml:"comments"`
+	Description        string              `xml:"description"`
+	EncodedContent     string              `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate            string              `xml:"pubDate"`
+	Date               string              `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Authors            []rssAuthor         `xml:"author"`
+	Creator            string              `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	EnclosureLinks     []rssEnclosure      `xml:"enclosure"`
+	OrigEnclosureLink  string              `xml:"http://rssnamespace.org/feedburner/ext/1.0 origEnclosureLink"`
+	ItunesDuration     string              `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ItunesEpisode      string              `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	ItunesSeason       string              `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd season"`
+	ItunesEpisodeType  string              `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episodeType"`
+	ItunesImage        rssItunesImage      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	ItunesExplicit     string              `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd explicit"`
+	ItunesSubtitle     string              `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd subtitle"`
+	PodcastTranscripts []podcastTranscript `xml:"https://podcastindex.org/namespace/1.0 transcript"`
+	PodcastChapters    *podcastChapters    `xml:"https://podcastindex.org/namespace/1.0 chapters"`
+	PodcastPersons     []podcastPerson     `xml:"https://podcastindex.org/namespace/1.0 person"`
+	PodcastValue       *podcastValue       `xml:"https://podcastindex.org/namespace/1.0 value"`
+	Categories         []rssCategory       `xml:"category"`
+	Source             rssSource           `xml:"source"`
+	DCSubject          []string            `xml:"http://purl.org/dc/elements/1.1/ subject"`
+	DCPublisher        string              `xml:"http://purl.org/dc/elements/1.1/ publisher"`
+	DCRights           string              `xml:"http://purl.org/dc/elements/1.1/ rights"`
+	DCLanguage         string              `xml:"http://purl.org/dc/elements/1.1/ language"`
+	DCIdentifier       string              `xml:"http://purl.org/dc/elements/1.1/ identifier"`
+	GeoRSSPoint        string              `xml:"http://www.georss.org/georss point"`
+	GeoRSSLine         string              `xml:"http://www.georss.org/georss line"`
+	GeoRSSPolygon      string              `xml:"http://www.georss.org/georss polygon"`
+	GeoRSSWhere        *georssWhere        `xml:"http://www.georss.org/georss where"`
 	media.Element
 }
 
+// georssWhere represents the GML encoding used by georss:where, e.g.
+// <georss:where><gml:Point><gml:pos>45.256 -71.92</gml:pos></gml:Point></georss:where>.
+// LineString and Polygon carry their coordinates in a gml:posList rather than
+// gml:pos; Polygon is read from both the GML 3.1 (exterior/LinearRing) and
+// the older GML 3.0 (outerBoundaryIs/LinearRing) spellings, since both are
+// seen in the wild.
+type georssWhere struct {
+	Point             string `xml:"http://www.opengis.net/gml Point>pos"`
+	LineString        string `xml:"http://www.opengis.net/gml LineString>posList"`
+	Polygon           string `xml:"http://www.opengis.net/gml Polygon>exterior>LinearRing>posList"`
+	PolygonOuterBound string `xml:"http://www.opengis.net/gml Polygon>outerBoundaryIs>LinearRing>posList"`
+}
+
+type rssCategory struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+	Domain  string `xml:"domain,attr"`
+}
+
+type rssSource struct {
+	XMLName xml.Name
+	Title   string `xml:",chardata"`
+	URL     string `xml:"url,attr"`
+}
+
+type rssItunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+type podcastTranscript struct {
+	URL      string `xml:"url,attr"`
+	Type     string `xml:"type,attr"`
+	Language string `xml:"language,attr"`
+	Rel      string `xml:"rel,attr"`
+}
+
+type podcastChapters struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type podcastPerson struct {
+	Name  string `xml:",chardata"`
+	Role  string `xml:"role,attr"`
+	Group string `xml:"group,attr"`
+	Img   string `xml:"img,attr"`
+	Href  string `xml:"href,attr"`
+}
+
+type podcastValue struct {
+	Type       string                  `xml:"type,attr"`
+	Method     string                  `xml:"method,attr"`
+	Suggested  string                  `xml:"suggested,attr"`
+	Recipients []podcastValueRecipient `xml:"https://podcastindex.org/namespace/1.0 valueRecipient"`
+}
+
+type podcastValueRecipient struct {
+	Name    string `xml:"name,attr"`
+	Type    string `xml:"type,attr"`
+	Address string `xml:"address,attr"`
+	Split   string `xml:"split,attr"`
+}
+
 func (r *rssFeed) SiteURL() string {
 	for _, element := range r.Links {
 		if element.XMLName.Space == "" {
@@ -102,10 +235,25 @@ func (r *rssFeed) FeedURL() string {
 	return ""
 }
 
+// HubURL returns the WebSub (PubSubHubbub) hub advertised via
+// atom:link rel="hub", if any.
+func (r *rssFeed) HubURL() string {
+	for _, element := range r.Links {
+		if element.XMLName.Space == "http://www.w3.org/2005/Atom" && element.Rel == "hub" && element.Href != "" {
+			return strings.TrimSpace(element.Href)
+		}
+	}
+
+	return ""
+}
+
 func (r *rssFeed) Transform() *model.Feed {
 	feed := new(model.Feed)
 	feed.SiteURL = r.SiteURL()
 	feed.FeedURL = r.FeedURL()
+	feed.HubURL = r.HubURL()
+	feed.CloudRegisterURL = r.Cloud.RegisterURL()
+	feed.CloudRegisterProcedure = r.Cloud.RegisterProcedure()
 	feed.Title = strings.TrimSpace(r.Title)
 
 	if feed.Title == "" {
@@ -172,8 +320,112 @@ func (r *rssItem) Author() string {
 	return r.Creator
 }
 
+func (r *rssItem) Podcast() *model.EntryPodcast {
+	if r.ItunesDuration == "" && r.ItunesEpisode == "" && r.ItunesSeason == "" &&
+		r.ItunesEpisodeType == "" && r.ItunesImage.Href == "" && r.ItunesExplicit == "" &&
+		r.ItunesSubtitle == "" && len(r.PodcastTranscripts) == 0 && r.PodcastChapters == nil &&
+		len(r.PodcastPersons) == 0 && r.PodcastValue == nil {
+		return nil
+	}
+
+	podcast := &model.EntryPodcast{
+		Duration:      itunesDurationToSeconds(r.ItunesDuration),
+		Episode:       r.ItunesEpisode,
+		Season:        r.ItunesSeason,
+		EpisodeType:   r.ItunesEpisodeType,
+		ImageURL:      r.ItunesImage.Href,
+		Explicit:      strings.EqualFold(r.ItunesExplicit, "yes") || strings.EqualFold(r.ItunesExplicit, "true"),
+		Subtitle:      strings.TrimSpace(r.ItunesSubtitle),
+		TranscriptURL: r.podcastTranscriptURL(),
+	}
+
+	if r.PodcastChapters != nil {
+		podcast.ChaptersURL = r.PodcastChapters.URL
+	}
+
+	for _, person := range r.PodcastPersons {
+		podcast.Persons = append(podcast.Persons, model.EntryPodcastPerson{
+			Name:     strings.TrimSpace(person.Name),
+			Role:     person.Role,
+			Group:    person.Group,
+			ImageURL: person.Img,
+			URL:      person.Href,
+		})
+	}
+
+	if r.PodcastValue != nil {
+		for _, recipient := range r.PodcastValue.Recipients {
+			podcast.ValueRecipients = append(podcast.ValueRecipients, model.EntryPodcastValueRecipient{
+				Name:    recipient.Name,
+				Type:    recipient.Type,
+				Address: recipient.Address,
+				Split:   recipient.Split,
+			})
+		}
+	}
+
+	return podcast
+}
+
+// podcastTranscriptURL returns the first transcript with no rel or with
+// rel="transcript", ahead of other renditions such as closed captions; if
+// there is none, it returns the first transcript listed.
+func (r *rssItem) podcastTranscriptURL() string {
+	for _, transcript := range r.PodcastTranscripts {
+		if transcript.Rel == "" || transcript.Rel == "transcript" {
+			return transcript.URL
+		}
+	}
+
+	if len(r.PodcastTranscripts) > 0 {
+		return r.PodcastTranscripts[0].URL
+	}
+
+	return ""
+}
+
+// itunesDurationToSeconds parses itunes:duration, which is either a plain
+// number of seconds or a colon-separated HH:MM:SS/MM:SS clock value. The
+// trailing component is parsed as a float since some feeds emit fractional
+// seconds (e.g. "1234.0"); it is truncated to a whole second.
+func itunesDurationToSeconds(value string) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	parts := strings.Split(value, ":")
+	seconds := 0
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+
+		if i == len(parts)-1 {
+			n, err := strconv.ParseFloat(part, 64)
+			if err != nil {
+				return 0
+			}
+			seconds = seconds*60 + int(n)
+			continue
+		}
+
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+
+	return seconds
+}
+
+// Hash computes the content-stable identifier used for deduplication.
+// dc:identifier is an additional fallback behind guid, used for feeds that
+// carry no guid but do keep a stable dc:identifier; it isn't checked ahead
+// of guid; doing so would change the hash of every already-stored entry
+// that happens to have one the moment this ships, re-emitting them all as
+// new.
 func (r *rssItem) Hash() string {
-	for _, value := range []string{r.GUID, r.URL()} {
+	for _, value := range []string{r.GUID, r.DCIdentifier, r.URL()} {
 		if value != "" {
 			return crypto.Hash(value)
 		}
@@ -182,6 +434,48 @@ func (r *rssItem) Hash() string {
 	return ""
 }
 
+// EntryDublinCore collects the Dublin Core Terms fields that have no
+// dedicated home on model.Entry (dc:creator and dc:date are handled by
+// Author() and PublishedDate()).
+func (r *rssItem) EntryDublinCore() *model.EntryDublinCore {
+	if len(r.DCSubject) == 0 && r.DCPublisher == "" && r.DCRights == "" &&
+		r.DCLanguage == "" && r.DCIdentifier == "" {
+		return nil
+	}
+
+	return &model.EntryDublinCore{
+		Subject:    r.DCSubject,
+		Publisher:  strings.TrimSpace(r.DCPublisher),
+		Rights:     strings.TrimSpace(r.DCRights),
+		Language:   strings.TrimSpace(r.DCLanguage),
+		Identifier: strings.TrimSpace(r.DCIdentifier),
+	}
+}
+
+// EntryGeo extracts the location of an item from its GeoRSS elements. The
+// GeoRSS Simple elements take precedence over georss:where (GML); within
+// each group a polygon is preferred over a line, and a line over a point.
+func (r *rssItem) EntryGeo() *model.EntryGeo {
+	switch {
+	case r.GeoRSSPolygon != "":
+		return &model.EntryGeo{Type: "polygon", Coordinates: r.GeoRSSPolygon}
+	case r.GeoRSSLine != "":
+		return &model.EntryGeo{Type: "line", Coordinates: r.GeoRSSLine}
+	case r.GeoRSSPoint != "":
+		return &model.EntryGeo{Type: "point", Coordinates: r.GeoRSSPoint}
+	case r.GeoRSSWhere != nil && r.GeoRSSWhere.Polygon != "":
+		return &model.EntryGeo{Type: "polygon", Coordinates: r.GeoRSSWhere.Polygon}
+	case r.GeoRSSWhere != nil && r.GeoRSSWhere.PolygonOuterBound != "":
+		return &model.EntryGeo{Type: "polygon", Coordinates: r.GeoRSSWhere.PolygonOuterBound}
+	case r.GeoRSSWhere != nil && r.GeoRSSWhere.LineString != "":
+		return &model.EntryGeo{Type: "line", Coordinates: r.GeoRSSWhere.LineString}
+	case r.GeoRSSWhere != nil && r.GeoRSSWhere.Point != "":
+		return &model.EntryGeo{Type: "point", Coordinates: r.GeoRSSWhere.Point}
+	default:
+		return nil
+	}
+}
+
 func (r *rssItem) Content() string {
 	if r.EncodedContent != "" {
 		return r.EncodedContent
@@ -269,6 +563,54 @@ func (r *rssItem) Enclosures() model.EnclosureList {
 	return enclosures
 }
 
+// Tags returns category values for simple tag-based filtering. The domain
+// attribute is preserved separately by EntryCategories, since collapsing it
+// here would discard the taxonomy a category belongs to.
+func (r *rssItem) Tags() []string {
+	var tags []string
+
+	for _, category := range r.Categories {
+		if value := strings.TrimSpace(category.Value); value != "" {
+			tags = append(tags, value)
+		}
+	}
+
+	return tags
+}
+
+// EntryCategories returns the full <category> elements, including the
+// domain attribute that names the taxonomy/scheme the category belongs to.
+func (r *rssItem) EntryCategories() []model.EntryCategory {
+	var categories []model.EntryCategory
+
+	for _, category := range r.Categories {
+		value := strings.TrimSpace(category.Value)
+		if value == "" {
+			continue
+		}
+
+		categories = append(categories, model.EntryCategory{
+			Value:  value,
+			Domain: strings.TrimSpace(category.Domain),
+		})
+	}
+
+	return categories
+}
+
+// EntrySource describes the originating feed item for re-syndicated
+// content, as advertised by the RSS 2.0 <source> element.
+func (r *rssItem) EntrySource() *model.EntrySource {
+	title := strings.TrimSpace(r.Source.Title)
+	sourceURL := strings.TrimSpace(r.Source.URL)
+
+	if title == "" && sourceURL == "" {
+		return nil
+	}
+
+	return &model.EntrySource{Title: title, URL: sourceURL}
+}
+
 func (r *rssItem) CommentsURL() string {
 	for _, commentLink := range r.CommentLinks {
 		if commentLink.XMLName.Space == "" {
@@ -289,6 +631,12 @@ func (r *rssItem) Transform() *model.Entry {
 	entry.Content = r.Content()
 	entry.Title = strings.TrimSpace(r.Title)
 	entry.Enclosures = r.Enclosures()
+	entry.Podcast = r.Podcast()
+	entry.Tags = r.Tags()
+	entry.Categories = r.EntryCategories()
+	entry.Source = r.EntrySource()
+	entry.DublinCore = r.EntryDublinCore()
+	entry.Geo = r.EntryGeo()
 	return entry
 }
 
@@ -0,0 +1,227 @@
+// Copyright 2017 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package rss // import "miniflux.app/reader/rss"
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func unmarshalItem(t *testing.T, data string) *rssItem {
+	t.Helper()
+	var item rssItem
+	if err := xml.Unmarshal([]byte(data), &item); err != nil {
+		t.Fatalf("unable to unmarshal item: %v", err)
+	}
+	return &item
+}
+
+func TestItunesDurationToSeconds(t *testing.T) {
+	scenarios := []struct {
+		value    string
+		expected int
+	}{
+		{"", 0},
+		{"1234", 1234},
+		{"1234.0", 1234},
+		{"1234.9", 1234},
+		{"02:03", 123},
+		{"01:02:03", 3723},
+		{"01:02:03.9", 3723},
+		{" 01:02:03 ", 3723},
+		{"not-a-number", 0},
+		{"01:not-a-number", 0},
+	}
+
+	for _, scenario := range scenarios {
+		result := itunesDurationToSeconds(scenario.value)
+		if result != scenario.expected {
+			t.Errorf("itunesDurationToSeconds(%q) = %d, want %d", scenario.value, result, scenario.expected)
+		}
+	}
+}
+
+func TestRSSItemPodcastFieldsFromItunesAndPodcastNamespace(t *testing.T) {
+	item := unmarshalItem(t, `
+		<item
+			xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"
+			xmlns:podcast="https://podcastindex.org/namespace/1.0">
+			<itunes:duration>01:02:03</itunes:duration>
+			<itunes:episode>5</itunes:episode>
+			<itunes:season>2</itunes:season>
+			<itunes:episodeType>full</itunes:episodeType>
+			<itunes:image href="https://example.org/cover.jpg"></itunes:image>
+			<itunes:explicit>yes</itunes:explicit>
+			<itunes:subtitle> A subtitle </itunes:subtitle>
+			<podcast:transcript url="https://example.org/transcript.vtt" type="text/vtt" rel="transcript"></podcast:transcript>
+			<podcast:chapters url="https://example.org/chapters.json" type="application/json+chapters"></podcast:chapters>
+			<podcast:person role="host" group="cast" img="https://example.org/host.jpg" href="https://example.org/host">Jane Doe</podcast:person>
+		</item>
+	`)
+
+	podcast := item.Podcast()
+	if podcast == nil {
+		t.Fatal("Podcast() = nil, want a populated EntryPodcast")
+	}
+
+	if podcast.Duration != 3723 {
+		t.Errorf("Duration = %d, want 3723", podcast.Duration)
+	}
+	if podcast.Episode != "5" || podcast.Season != "2" || podcast.EpisodeType != "full" {
+		t.Errorf("Episode/Season/EpisodeType = %q/%q/%q, want 5/2/full", podcast.Episode, podcast.Season, podcast.EpisodeType)
+	}
+	if podcast.ImageURL != "https://example.org/cover.jpg" {
+		t.Errorf("ImageURL = %q, want the itunes:image href", podcast.ImageURL)
+	}
+	if !podcast.Explicit {
+		t.Error("Explicit = false, want true for itunes:explicit=yes")
+	}
+	if podcast.Subtitle != "A subtitle" {
+		t.Errorf("Subtitle = %q, want trimmed %q", podcast.Subtitle, "A subtitle")
+	}
+	if podcast.TranscriptURL != "https://example.org/transcript.vtt" {
+		t.Errorf("TranscriptURL = %q, want the rel=transcript entry", podcast.TranscriptURL)
+	}
+	if podcast.ChaptersURL != "https://example.org/chapters.json" {
+		t.Errorf("ChaptersURL = %q, want the podcast:chapters url", podcast.ChaptersURL)
+	}
+	if len(podcast.Persons) != 1 || podcast.Persons[0].Name != "Jane Doe" || podcast.Persons[0].Role != "host" {
+		t.Errorf("Persons = %+v, want a single host named Jane Doe", podcast.Persons)
+	}
+}
+
+func TestRSSItemPodcastNilWhenNoFieldsPresent(t *testing.T) {
+	item := unmarshalItem(t, `<item></item>`)
+	if podcast := item.Podcast(); podcast != nil {
+		t.Errorf("Podcast() = %+v, want nil when no podcast tags are present", podcast)
+	}
+}
+
+func TestRSSItemHashPrefersGUIDOverDublinCoreIdentifier(t *testing.T) {
+	item := unmarshalItem(t, `
+		<item>
+			<guid>guid-123</guid>
+			<dc:identifier xmlns:dc="http://purl.org/dc/elements/1.1/">dc-456</dc:identifier>
+			<link>https://example.org/post</link>
+		</item>
+	`)
+
+	expected := unmarshalItem(t, `<item><guid>guid-123</guid></item>`).Hash()
+	if hash := item.Hash(); hash != expected {
+		t.Errorf("Hash() = %q, want the guid-derived hash %q; guid must win over dc:identifier to avoid a mass rehash on upgrade", hash, expected)
+	}
+}
+
+func TestRSSItemHashFallsBackToDublinCoreIdentifierWithoutGUID(t *testing.T) {
+	item := unmarshalItem(t, `
+		<item>
+			<dc:identifier xmlns:dc="http://purl.org/dc/elements/1.1/">dc-456</dc:identifier>
+			<link>https://example.org/post</link>
+		</item>
+	`)
+
+	expected := unmarshalItem(t, `<item><dc:identifier xmlns:dc="http://purl.org/dc/elements/1.1/">dc-456</dc:identifier></item>`).Hash()
+	if hash := item.Hash(); hash != expected {
+		t.Errorf("Hash() = %q, want the dc:identifier-derived hash %q when no guid is present", hash, expected)
+	}
+}
+
+func TestRSSItemHashFallsBackToURL(t *testing.T) {
+	item := unmarshalItem(t, `<item><link>https://example.org/post</link></item>`)
+	if item.Hash() == "" {
+		t.Error("Hash() should fall back to the URL when there's no guid or dc:identifier")
+	}
+}
+
+func TestRSSItemEntryGeoPrefersSimpleOverWhere(t *testing.T) {
+	item := unmarshalItem(t, `
+		<item xmlns:georss="http://www.georss.org/georss" xmlns:gml="http://www.opengis.net/gml">
+			<georss:point>45.256 -71.92</georss:point>
+			<georss:where>
+				<gml:Point><gml:pos>1 2</gml:pos></gml:Point>
+			</georss:where>
+		</item>
+	`)
+
+	geo := item.EntryGeo()
+	if geo == nil || geo.Type != "point" || geo.Coordinates != "45.256 -71.92" {
+		t.Errorf("EntryGeo() = %+v, want the georss:point simple element", geo)
+	}
+}
+
+func TestRSSItemEntryGeoWhereGMLPoint(t *testing.T) {
+	item := unmarshalItem(t, `
+		<item xmlns:georss="http://www.georss.org/georss" xmlns:gml="http://www.opengis.net/gml">
+			<georss:where>
+				<gml:Point><gml:pos>45.256 -71.92</gml:pos></gml:Point>
+			</georss:where>
+		</item>
+	`)
+
+	geo := item.EntryGeo()
+	if geo == nil || geo.Type != "point" || geo.Coordinates != "45.256 -71.92" {
+		t.Errorf("EntryGeo() = %+v, want a point from georss:where", geo)
+	}
+}
+
+func TestRSSItemEntryGeoWhereGMLLineString(t *testing.T) {
+	item := unmarshalItem(t, `
+		<item xmlns:georss="http://www.georss.org/georss" xmlns:gml="http://www.opengis.net/gml">
+			<georss:where>
+				<gml:LineString><gml:posList>45.256 -71.92 46.46 -66.29</gml:posList></gml:LineString>
+			</georss:where>
+		</item>
+	`)
+
+	geo := item.EntryGeo()
+	if geo == nil || geo.Type != "line" || geo.Coordinates != "45.256 -71.92 46.46 -66.29" {
+		t.Errorf("EntryGeo() = %+v, want a line from georss:where's gml:LineString", geo)
+	}
+}
+
+func TestRSSItemEntryGeoWhereGMLPolygon(t *testing.T) {
+	item := unmarshalItem(t, `
+		<item xmlns:georss="http://www.georss.org/georss" xmlns:gml="http://www.opengis.net/gml">
+			<georss:where>
+				<gml:Polygon>
+					<gml:exterior>
+						<gml:LinearRing><gml:posList>45 -71 46 -71 46 -70 45 -71</gml:posList></gml:LinearRing>
+					</gml:exterior>
+				</gml:Polygon>
+			</georss:where>
+		</item>
+	`)
+
+	geo := item.EntryGeo()
+	if geo == nil || geo.Type != "polygon" || geo.Coordinates != "45 -71 46 -71 46 -70 45 -71" {
+		t.Errorf("EntryGeo() = %+v, want a polygon from georss:where's gml:Polygon>exterior", geo)
+	}
+}
+
+func TestRSSItemEntryGeoWhereGMLPolygonOuterBoundaryIs(t *testing.T) {
+	item := unmarshalItem(t, `
+		<item xmlns:georss="http://www.georss.org/georss" xmlns:gml="http://www.opengis.net/gml">
+			<georss:where>
+				<gml:Polygon>
+					<gml:outerBoundaryIs>
+						<gml:LinearRing><gml:posList>45 -71 46 -71 46 -70 45 -71</gml:posList></gml:LinearRing>
+					</gml:outerBoundaryIs>
+				</gml:Polygon>
+			</georss:where>
+		</item>
+	`)
+
+	geo := item.EntryGeo()
+	if geo == nil || geo.Type != "polygon" || geo.Coordinates != "45 -71 46 -71 46 -70 45 -71" {
+		t.Errorf("EntryGeo() = %+v, want a polygon from georss:where's older gml:Polygon>outerBoundaryIs spelling", geo)
+	}
+}
+
+func TestRSSItemEntryGeoNoGeo(t *testing.T) {
+	item := unmarshalItem(t, `<item></item>`)
+	if geo := item.EntryGeo(); geo != nil {
+		t.Errorf("EntryGeo() = %+v, want nil when no GeoRSS element is present", geo)
+	}
+}
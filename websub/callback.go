@@ -0,0 +1,77 @@
+// Copyright 2017 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package websub // import "miniflux.app/websub"
+
+import (
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+
+	"miniflux.app/logger"
+)
+
+// FeedIngester ingests content pushed to a feed's WebSub/RSS Cloud callback
+// through the regular parser pipeline, and tracks the subscriptions that
+// callback belongs to. It's implemented by the worker package so this
+// package doesn't need to depend on storage directly.
+type FeedIngester interface {
+	IngestPushedContent(callbackToken string, body io.Reader) error
+
+	// ConfirmSubscription verifies that callbackToken has a pending
+	// subscribe/unsubscribe request matching mode and topic, and, if so,
+	// records the hub-granted leaseSeconds (0 if the hub didn't send one,
+	// in which case the implementation keeps whatever lease was originally
+	// requested). It returns an error if no such request is pending, which
+	// Handler reports as 404 rather than confirming a challenge for a
+	// subscription we never asked for.
+	ConfirmSubscription(callbackToken, mode, topic string, leaseSeconds int) error
+}
+
+// Handler builds the HTTP handler mounted at /websub/callback/{token}. It
+// answers the hub's subscription-verification GET and accepts the pushed
+// content on POST, per the WebSub spec; RSS Cloud notifications reuse the
+// same POST leg since both deliver the updated document to our callback.
+func Handler(ingester FeedIngester) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := path.Base(r.URL.Path)
+
+		switch r.Method {
+		case http.MethodGet:
+			query := r.URL.Query()
+			mode := query.Get("hub.mode")
+			topic := query.Get("hub.topic")
+			challenge := query.Get("hub.challenge")
+
+			if mode == "" || topic == "" || challenge == "" {
+				http.Error(w, "missing hub.mode, hub.topic or hub.challenge", http.StatusBadRequest)
+				return
+			}
+
+			// hub.lease_seconds is absent for unsubscribe confirmations and
+			// optional even for subscribe ones; 0 tells ConfirmSubscription
+			// to keep whatever lease we originally requested.
+			leaseSeconds, _ := strconv.Atoi(query.Get("hub.lease_seconds"))
+
+			if err := ingester.ConfirmSubscription(token, mode, topic, leaseSeconds); err != nil {
+				logger.Error("[WebSub] callback %q: %v", token, err)
+				http.Error(w, "no matching pending subscription", http.StatusNotFound)
+				return
+			}
+
+			w.Write([]byte(challenge))
+		case http.MethodPost:
+			if err := ingester.IngestPushedContent(token, r.Body); err != nil {
+				logger.Error("[WebSub] callback %q: %v", token, err)
+				http.Error(w, "unable to process push", http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
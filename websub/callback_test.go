@@ -0,0 +1,116 @@
+// Copyright 2017 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package websub // import "miniflux.app/websub"
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type stubIngester struct {
+	confirmErr     error
+	confirmedMode  string
+	confirmedTopic string
+	confirmedLease int
+	ingestErr      error
+	ingestedBody   string
+}
+
+func (s *stubIngester) ConfirmSubscription(callbackToken, mode, topic string, leaseSeconds int) error {
+	s.confirmedMode = mode
+	s.confirmedTopic = topic
+	s.confirmedLease = leaseSeconds
+	return s.confirmErr
+}
+
+func (s *stubIngester) IngestPushedContent(callbackToken string, body io.Reader) error {
+	data, _ := io.ReadAll(body)
+	s.ingestedBody = string(data)
+	return s.ingestErr
+}
+
+func TestHandlerGETConfirmsAndEchoesChallenge(t *testing.T) {
+	ingester := &stubIngester{}
+	handler := Handler(ingester)
+
+	request := httptest.NewRequest(http.MethodGet, "/websub/callback/abc123?hub.mode=subscribe&hub.topic=https://example.org/feed.xml&hub.challenge=xyz&hub.lease_seconds=86400", nil)
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", recorder.Code)
+	}
+	if recorder.Body.String() != "xyz" {
+		t.Errorf("body = %q, want the echoed challenge %q", recorder.Body.String(), "xyz")
+	}
+	if ingester.confirmedMode != "subscribe" || ingester.confirmedTopic != "https://example.org/feed.xml" {
+		t.Errorf("ConfirmSubscription got mode=%q topic=%q, want subscribe/https://example.org/feed.xml", ingester.confirmedMode, ingester.confirmedTopic)
+	}
+	if ingester.confirmedLease != 86400 {
+		t.Errorf("confirmedLease = %d, want 86400", ingester.confirmedLease)
+	}
+}
+
+func TestHandlerGETRejectsUnknownSubscription(t *testing.T) {
+	ingester := &stubIngester{confirmErr: errors.New("no pending subscription for token")}
+	handler := Handler(ingester)
+
+	request := httptest.NewRequest(http.MethodGet, "/websub/callback/unknown-token?hub.mode=subscribe&hub.topic=https://example.org/feed.xml&hub.challenge=xyz", nil)
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404 when the token/topic pair has no pending subscription", recorder.Code)
+	}
+	if recorder.Body.String() == "xyz\n" || strings.Contains(recorder.Body.String(), "xyz") {
+		t.Errorf("body = %q, the challenge must not be echoed back for an unverified subscription", recorder.Body.String())
+	}
+}
+
+func TestHandlerGETRequiresModeAndTopic(t *testing.T) {
+	ingester := &stubIngester{}
+	handler := Handler(ingester)
+
+	request := httptest.NewRequest(http.MethodGet, "/websub/callback/abc123?hub.challenge=xyz", nil)
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400 when hub.mode/hub.topic are missing", recorder.Code)
+	}
+}
+
+func TestHandlerPOSTIngestsBody(t *testing.T) {
+	ingester := &stubIngester{}
+	handler := Handler(ingester)
+
+	request := httptest.NewRequest(http.MethodPost, "/websub/callback/abc123", strings.NewReader("<rss></rss>"))
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", recorder.Code)
+	}
+	if ingester.ingestedBody != "<rss></rss>" {
+		t.Errorf("ingestedBody = %q, want the raw POST body", ingester.ingestedBody)
+	}
+}
+
+func TestHandlerRejectsOtherMethods(t *testing.T) {
+	ingester := &stubIngester{}
+	handler := Handler(ingester)
+
+	request := httptest.NewRequest(http.MethodDelete, "/websub/callback/abc123", nil)
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", recorder.Code)
+	}
+}
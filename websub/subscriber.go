@@ -0,0 +1,161 @@
+// Copyright 2017 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+// Package websub implements the subscriber side of WebSub (formerly
+// PubSubHubbub), with a REST-profile RSS Cloud fallback for feeds that only
+// advertise a <cloud> element.
+package websub // import "miniflux.app/websub"
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"miniflux.app/config"
+	"miniflux.app/crypto"
+	"miniflux.app/logger"
+	"miniflux.app/model"
+)
+
+// leaseRenewalWindow is how long before a WebSub lease expiry miniflux
+// re-subscribes, to leave room for a retry if the hub is briefly unreachable.
+const leaseRenewalWindow = 24 * time.Hour
+
+// defaultLeaseSeconds is requested when a hub doesn't otherwise negotiate one.
+const defaultLeaseSeconds = 10 * 24 * 60 * 60
+
+// cloudRegistrationLifetime is how long an RSS Cloud registration is assumed
+// to last before the publisher forgets it. The protocol doesn't negotiate a
+// lease the way WebSub does, so this is our own re-registration schedule
+// rather than a value any endpoint reports back to us.
+const cloudRegistrationLifetime = 24 * time.Hour
+
+// cloudRenewalWindow is how long before that assumed expiry miniflux
+// re-registers.
+const cloudRenewalWindow = 2 * time.Hour
+
+// Subscriber sends WebSub subscription requests and RSS Cloud registrations
+// on behalf of feeds that advertise a hub or a cloud endpoint.
+type Subscriber struct {
+	httpClient  *http.Client
+	callbackURL string
+}
+
+// NewSubscriber creates a Subscriber whose callback URLs are built under the
+// given public base URL, e.g. "https://miniflux.example.com".
+func NewSubscriber(baseURL string) *Subscriber {
+	return &Subscriber{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		callbackURL: strings.TrimRight(baseURL, "/") + "/websub/callback",
+	}
+}
+
+// Subscribe registers for push updates on the given feed: WebSub when a hub
+// is advertised, RSS Cloud otherwise. It's a no-op when the feature is
+// disabled in config or the feed advertises neither.
+func (s *Subscriber) Subscribe(feed *model.Feed) error {
+	if !config.Opts.WebSubEnabled() {
+		return nil
+	}
+
+	switch {
+	case feed.HubURL != "":
+		return s.subscribeWebSub(feed)
+	case feed.CloudRegisterURL != "":
+		return s.registerCloud(feed)
+	default:
+		return nil
+	}
+}
+
+// NeedsRenewal reports whether feed's WebSub lease or RSS Cloud registration
+// is close enough to expiry that it should be renewed before it lapses.
+func (s *Subscriber) NeedsRenewal(feed *model.Feed) bool {
+	switch {
+	case feed.HubURL != "":
+		return !feed.WebSubLeaseExpiresAt.IsZero() &&
+			time.Until(feed.WebSubLeaseExpiresAt) < leaseRenewalWindow
+	case feed.CloudRegisterURL != "":
+		return !feed.CloudRegistrationExpiresAt.IsZero() &&
+			time.Until(feed.CloudRegistrationExpiresAt) < cloudRenewalWindow
+	default:
+		return false
+	}
+}
+
+func (s *Subscriber) subscribeWebSub(feed *model.Feed) error {
+	if feed.WebSubCallbackToken == "" {
+		feed.WebSubCallbackToken = crypto.GenerateRandomString(32)
+	}
+
+	form := url.Values{}
+	form.Set("hub.mode", "subscribe")
+	form.Set("hub.topic", feed.FeedURL)
+	form.Set("hub.callback", fmt.Sprintf("%s/%s", s.callbackURL, feed.WebSubCallbackToken))
+	form.Set("hub.lease_seconds", strconv.Itoa(defaultLeaseSeconds))
+
+	request, err := http.NewRequest(http.MethodPost, feed.HubURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("websub: unable to build subscribe request for %q: %v", feed.HubURL, err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("websub: unable to reach hub %q: %v", feed.HubURL, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusAccepted && response.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("websub: hub %q rejected subscription with status %d", feed.HubURL, response.StatusCode)
+	}
+
+	// The hub hasn't granted anything yet: per the WebSub spec it still has
+	// to verify the subscription with a GET to our callback before it's
+	// active, and that's the only place the actually-granted hub.lease_seconds
+	// shows up. feed.WebSubLeaseExpiresAt stays zero (see its doc comment)
+	// until that GET lands; the FeedIngester's ConfirmSubscription, which
+	// Handler calls for that GET, is responsible for setting it.
+	logger.Debug("[WebSub] Requested subscription to hub %q for feed %q", feed.HubURL, feed.FeedURL)
+	return nil
+}
+
+// registerCloud performs the REST ("http-post") RSS Cloud registration
+// profile: a subscriber POSTs its own callback URL, the topic it wants
+// notifications for and the registerProcedure name to the publisher's cloud
+// endpoint (feed.CloudRegisterURL).
+func (s *Subscriber) registerCloud(feed *model.Feed) error {
+	if feed.WebSubCallbackToken == "" {
+		feed.WebSubCallbackToken = crypto.GenerateRandomString(32)
+	}
+
+	form := url.Values{}
+	form.Set("url1", feed.FeedURL)
+	form.Set("procedure", feed.CloudRegisterProcedure)
+	form.Set("protocol", "http-post")
+	form.Set("notifyUrl", fmt.Sprintf("%s/%s", s.callbackURL, feed.WebSubCallbackToken))
+
+	request, err := http.NewRequest(http.MethodPost, feed.CloudRegisterURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("websub: unable to build cloud registration request for %q: %v", feed.CloudRegisterURL, err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("websub: unable to reach cloud endpoint %q: %v", feed.CloudRegisterURL, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 400 {
+		return fmt.Errorf("websub: cloud endpoint %q rejected registration with status %d", feed.CloudRegisterURL, response.StatusCode)
+	}
+
+	feed.CloudRegistrationExpiresAt = time.Now().Add(cloudRegistrationLifetime)
+	logger.Debug("[WebSub] Registered with RSS Cloud endpoint %q for feed %q", feed.CloudRegisterURL, feed.FeedURL)
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2017 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package websub // import "miniflux.app/websub"
+
+import (
+	"testing"
+	"time"
+
+	"miniflux.app/model"
+)
+
+func TestNeedsRenewalWebSub(t *testing.T) {
+	subscriber := NewSubscriber("https://miniflux.example.com")
+
+	scenarios := []struct {
+		name     string
+		feed     *model.Feed
+		expected bool
+	}{
+		{
+			name:     "no subscription yet",
+			feed:     &model.Feed{HubURL: "https://hub.example.com/"},
+			expected: false,
+		},
+		{
+			name: "far from expiry",
+			feed: &model.Feed{
+				HubURL:               "https://hub.example.com/",
+				WebSubLeaseExpiresAt: time.Now().Add(10 * 24 * time.Hour),
+			},
+			expected: false,
+		},
+		{
+			name: "inside the renewal window",
+			feed: &model.Feed{
+				HubURL:               "https://hub.example.com/",
+				WebSubLeaseExpiresAt: time.Now().Add(1 * time.Hour),
+			},
+			expected: true,
+		},
+		{
+			name: "already expired",
+			feed: &model.Feed{
+				HubURL:               "https://hub.example.com/",
+				WebSubLeaseExpiresAt: time.Now().Add(-1 * time.Hour),
+			},
+			expected: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		if result := subscriber.NeedsRenewal(scenario.feed); result != scenario.expected {
+			t.Errorf("%s: NeedsRenewal() = %v, want %v", scenario.name, result, scenario.expected)
+		}
+	}
+}
+
+func TestNeedsRenewalRSSCloud(t *testing.T) {
+	subscriber := NewSubscriber("https://miniflux.example.com")
+
+	scenarios := []struct {
+		name     string
+		feed     *model.Feed
+		expected bool
+	}{
+		{
+			name:     "no registration yet",
+			feed:     &model.Feed{CloudRegisterURL: "https://publisher.example.com/cloud"},
+			expected: false,
+		},
+		{
+			name: "far from expiry",
+			feed: &model.Feed{
+				CloudRegisterURL:           "https://publisher.example.com/cloud",
+				CloudRegistrationExpiresAt: time.Now().Add(20 * time.Hour),
+			},
+			expected: false,
+		},
+		{
+			name: "inside the renewal window",
+			feed: &model.Feed{
+				CloudRegisterURL:           "https://publisher.example.com/cloud",
+				CloudRegistrationExpiresAt: time.Now().Add(30 * time.Minute),
+			},
+			expected: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		if result := subscriber.NeedsRenewal(scenario.feed); result != scenario.expected {
+			t.Errorf("%s: NeedsRenewal() = %v, want %v", scenario.name, result, scenario.expected)
+		}
+	}
+}
+
+func TestNeedsRenewalNeitherHubNorCloud(t *testing.T) {
+	subscriber := NewSubscriber("https://miniflux.example.com")
+	if subscriber.NeedsRenewal(&model.Feed{}) {
+		t.Error("NeedsRenewal() = true, want false for a feed with no hub and no cloud endpoint")
+	}
+}